@@ -0,0 +1,566 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"math/bits"
+	"math/rand"
+	"net"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+	"github.com/google/gopacket/pcap"
+	"github.com/jonasbostoen/go-fingerprint/report"
+	"github.com/jonasbostoen/go-fingerprint/ouidb"
+)
+
+// Interface represents an interface
+type Interface struct {
+	iface   *net.Interface
+	ip      net.IP
+	netmask net.IPMask
+	prefix  uint8
+}
+
+var (
+	iface    = flag.String("i", "wi-fi", "Interface to scan on")
+	all      = flag.Bool("all", false, "Scan every up interface with an IPv4 address concurrently")
+	interval = flag.Duration("t", 5*time.Second, "Interval between re-ARP sweeps")
+	ports    = flag.String("p", "", "Port range to SYN scan on discovered hosts, e.g. 22-1024")
+	outMode  = flag.String("o", "table", "Output format: table, json, csv or pcap")
+	outPath  = flag.String("out", "", "Output file for json/csv/pcap (default stdout, or scan.pcap for pcap)")
+	passive  = flag.Bool("passive", false, "Don't send ARP requests, just sniff arp/mDNS traffic for hosts")
+)
+
+// hostnames holds hostnames recovered from mDNS A records, keyed by IP, for
+// hosts that examineMAC's reverse DNS lookup doesn't resolve on its own.
+var (
+	hostnames   = make(map[string]string)
+	hostnamesMu sync.Mutex
+)
+
+// arpTable tracks hosts already discovered (IP -> MAC) so results stay
+// deduped across interfaces and can be reused to resolve a gateway MAC.
+var (
+	arpTable = make(map[string]net.HardwareAddr)
+	arpMu    sync.Mutex
+)
+
+// openPorts tracks the open ports found per host by probeHost.
+var (
+	openPorts   = make(map[string][]int)
+	openPortsMu sync.Mutex
+)
+
+func init() {
+	// gopacket only maps UDP/53 to DNS by default; mDNS runs on 5353, so
+	// -passive's decode of captured packets would never see a DNS layer
+	// without registering the port too.
+	layers.RegisterUDPPortLayerType(layers.UDPPort(5353), layers.LayerTypeDNS)
+}
+
+func main() {
+	flag.Parse()
+
+	if *passive && *ports != "" {
+		log.Println("[*] -passive set; ignoring -p, passive mode never sends probes")
+	}
+
+	reporter, err := report.New(*outMode, *outPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer reporter.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt)
+	go func() {
+		<-sig
+		log.Println("\n[*] Stopping...")
+		cancel()
+	}()
+
+	var scanners []*Interface
+	if *all {
+		scanners, err = getInterfaces()
+	} else if scanner, e := getInterface(); e != nil {
+		err = e
+	} else {
+		scanners = []*Interface{scanner}
+	}
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for _, scanner := range scanners {
+		wg.Add(1)
+		go func(s *Interface) {
+			defer wg.Done()
+
+			scan := arpScan
+			if *passive {
+				scan = passiveScan
+			}
+			if err := scan(ctx, s, reporter); err != nil {
+				log.Printf("[!] %s: %v", s.iface.Name, err)
+			}
+		}(scanner)
+	}
+	wg.Wait()
+}
+
+/// Gets interface based on flag (or default wi-fi)
+func getInterface() (*Interface, error) {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, ifs := range ifaces {
+		if strings.EqualFold(ifs.Name, *iface) {
+			return resolveInterface(ifs)
+		}
+	}
+
+	return nil, errors.New("Interface not found: " + *iface)
+}
+
+// getInterfaces resolves every up interface with an IPv4 address, for -all mode.
+func getInterfaces() ([]*Interface, error) {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return nil, err
+	}
+
+	var scanners []*Interface
+	for _, ifs := range ifaces {
+		if !strings.Contains(ifs.Flags.String(), "up") {
+			continue
+		}
+
+		scanner, err := resolveInterface(ifs)
+		if err != nil || scanner.ip == nil {
+			continue
+		}
+		scanners = append(scanners, scanner)
+	}
+
+	if len(scanners) == 0 {
+		return nil, errors.New("no up interfaces with an IPv4 address found")
+	}
+
+	return scanners, nil
+}
+
+// resolveInterface pairs a net.Interface with its IPv4 address and matching pcap device.
+func resolveInterface(ifs net.Interface) (*Interface, error) {
+	if !strings.Contains(ifs.Flags.String(), "up") {
+		return nil, errors.New("Interface is down: " + ifs.Name)
+	}
+
+	var ip net.IP
+	addrs, err := ifs.Addrs()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, a := range addrs {
+		if ipnet, ok := a.(*net.IPNet); ok {
+			if ip4 := ipnet.IP.To4(); ip4 != nil {
+				ip = ip4
+			}
+		}
+	}
+
+	i, err := net.InterfaceByIndex(ifs.Index)
+	if err != nil {
+		return nil, err
+	}
+	scanner := Interface{iface: i}
+
+	devs, err := pcap.FindAllDevs()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, dev := range devs {
+		for _, addr := range dev.Addresses {
+			if ip4 := addr.IP.To4(); ip4 != nil {
+				if bytes.Compare(ip, ip4) == 0 {
+					scanner.iface.Name = dev.Name
+					scanner.ip = ip4
+					scanner.netmask = addr.Netmask
+					scanner.prefix = uint8(bits.OnesCount32(binary.BigEndian.Uint32(addr.Netmask)))
+				}
+			}
+		}
+	}
+
+	return &scanner, nil
+}
+
+// arpScan scans the network using the interface provided, re-sweeping every
+// -t interval until ctx is cancelled so late-joining hosts are discovered.
+func arpScan(ctx context.Context, scanner *Interface, reporter report.Reporter) error {
+	handle, err := pcap.OpenLive(scanner.iface.Name, 1024, false, pcap.BlockForever)
+	if err != nil {
+		return err
+	}
+	defer handle.Close()
+
+	// Start reading ARP packets in a goroutine
+	stop := make(chan struct{})
+	go readARP(scanner, handle, scanner.iface, stop, reporter)
+	defer close(stop)
+
+	// Set up the layers
+	eth := layers.Ethernet{
+		SrcMAC:       scanner.iface.HardwareAddr,
+		DstMAC:       net.HardwareAddr{0xff, 0xff, 0xff, 0xff, 0xff, 0xff},
+		EthernetType: layers.EthernetTypeARP,
+	}
+	arp := layers.ARP{
+		AddrType:          layers.LinkTypeEthernet,
+		Protocol:          layers.EthernetTypeIPv4,
+		HwAddressSize:     6,
+		ProtAddressSize:   4,
+		Operation:         layers.ARPRequest,
+		SourceHwAddress:   []byte(scanner.iface.HardwareAddr),
+		SourceProtAddress: []byte(scanner.ip),
+		DstHwAddress:      []byte{0, 0, 0, 0, 0, 0},
+	}
+
+	// Set up buffer and options for serialization.
+	buf := gopacket.NewSerializeBuffer()
+	opts := gopacket.SerializeOptions{
+		FixLengths:       true,
+		ComputeChecksums: true,
+	}
+
+	log.Printf("\n[*] Scanning on %s: %s [%s/%d]\n", scanner.iface.Name, scanner.ip, scanner.ip.Mask(scanner.netmask), scanner.prefix)
+
+	ticker := time.NewTicker(*interval)
+	defer ticker.Stop()
+
+	for {
+		// Start sending ARP requests
+		for _, ip := range getIPAddresses(&scanner.ip, &scanner.netmask) {
+			arp.DstProtAddress = []byte(ip)
+			gopacket.SerializeLayers(buf, opts, &eth, &arp)
+			if err := handle.WritePacketData(buf.Bytes()); err != nil {
+				return err
+			}
+			reporter.WritePacket(buf.Bytes(), gopacket.CaptureInfo{
+				Timestamp:     time.Now(),
+				CaptureLength: len(buf.Bytes()),
+				Length:        len(buf.Bytes()),
+			})
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func readARP(scanner *Interface, handle *pcap.Handle, iface *net.Interface, stop chan struct{}, reporter report.Reporter) {
+	src := gopacket.NewPacketSource(handle, layers.LayerTypeEthernet)
+	in := src.Packets()
+
+	for {
+		var packet gopacket.Packet
+		select {
+		case <-stop:
+			return
+		case packet = <-in:
+			arpLayer := packet.Layer(layers.LayerTypeARP)
+			if arpLayer == nil {
+				continue
+			}
+			arp := arpLayer.(*layers.ARP)
+			if arp.Operation != layers.ARPReply || bytes.Equal([]byte(iface.HardwareAddr), arp.SourceHwAddress) {
+				// This is a packet I sent.
+				continue
+			}
+
+			reporter.WritePacket(packet.Data(), packet.Metadata().CaptureInfo)
+			go examineMAC(scanner, arp.SourceProtAddress, arp.SourceHwAddress, reporter)
+		}
+	}
+}
+
+func examineMAC(scanner *Interface, ip, mac []byte, reporter report.Reporter) {
+	key := net.IP(ip).String()
+	arpMu.Lock()
+	if _, ok := arpTable[key]; ok {
+		arpMu.Unlock()
+		return
+	}
+	arpTable[key] = net.HardwareAddr(mac)
+	arpMu.Unlock()
+
+	host := report.Host{
+		Timestamp: time.Now(),
+		Interface: scanner.iface.Name,
+		IPv4:      net.IP(ip).String(),
+		MAC:       net.HardwareAddr(mac).String(),
+		Vendor:    ouidb.Lookup(net.HardwareAddr(mac)),
+	}
+
+	if names, err := net.LookupAddr(host.IPv4); err == nil && len(names) > 0 {
+		host.Hostname = strings.TrimSuffix(names[0], ".")
+	} else {
+		hostnamesMu.Lock()
+		host.Hostname = hostnames[host.IPv4]
+		hostnamesMu.Unlock()
+	}
+
+	// -passive is a stealthier discovery option precisely because it never
+	// sends anything; don't undo that by firing active SYN probes at hosts
+	// it turns up.
+	if *ports != "" && !*passive {
+		host.Ports = probeHost(scanner, net.HardwareAddr(mac), net.IP(ip))
+		if len(host.Ports) > 0 {
+			openPortsMu.Lock()
+			openPorts[key] = host.Ports
+			openPortsMu.Unlock()
+		}
+	}
+
+	if err := reporter.ReportHost(host); err != nil {
+		log.Printf("[!] report %s: %v", host.IPv4, err)
+	}
+}
+
+// passiveScan skips sending any ARP requests and instead sniffs gratuitous
+// ARPs, ARP requests from other hosts, and mDNS announcements, to find
+// hosts that rate-limit or ignore unsolicited probes.
+func passiveScan(ctx context.Context, scanner *Interface, reporter report.Reporter) error {
+	handle, err := pcap.OpenLive(scanner.iface.Name, 1600, false, pcap.BlockForever)
+	if err != nil {
+		return err
+	}
+	defer handle.Close()
+
+	if err := handle.SetBPFFilter("arp or (udp and port 5353)"); err != nil {
+		return err
+	}
+
+	log.Printf("\n[*] Passively sniffing on %s: %s [%s/%d]\n", scanner.iface.Name, scanner.ip, scanner.ip.Mask(scanner.netmask), scanner.prefix)
+
+	src := gopacket.NewPacketSource(handle, layers.LayerTypeEthernet)
+	in := src.Packets()
+
+	for {
+		var packet gopacket.Packet
+		select {
+		case <-ctx.Done():
+			return nil
+		case packet = <-in:
+			reporter.WritePacket(packet.Data(), packet.Metadata().CaptureInfo)
+
+			if arpLayer := packet.Layer(layers.LayerTypeARP); arpLayer != nil {
+				arp := arpLayer.(*layers.ARP)
+				if !bytes.Equal([]byte(scanner.iface.HardwareAddr), arp.SourceHwAddress) {
+					go examineMAC(scanner, arp.SourceProtAddress, arp.SourceHwAddress, reporter)
+				}
+				continue
+			}
+
+			if dnsLayer := packet.Layer(layers.LayerTypeDNS); dnsLayer != nil {
+				handleMDNS(scanner, packet, dnsLayer.(*layers.DNS), reporter)
+			}
+		}
+	}
+}
+
+// handleMDNS recovers hostnames and advertised service types from an mDNS
+// packet and merges any new hosts into the same result stream as active
+// ARP discovery.
+func handleMDNS(scanner *Interface, packet gopacket.Packet, dns *layers.DNS, reporter report.Reporter) {
+	ethLayer := packet.Layer(layers.LayerTypeEthernet)
+	if ethLayer == nil {
+		return
+	}
+	srcMAC := ethLayer.(*layers.Ethernet).SrcMAC
+
+	for _, rr := range append(dns.Answers, dns.Additionals...) {
+		switch rr.Type {
+		case layers.DNSTypeA:
+			hostnamesMu.Lock()
+			hostnames[rr.IP.String()] = strings.TrimSuffix(string(rr.Name), ".")
+			hostnamesMu.Unlock()
+
+			go examineMAC(scanner, rr.IP.To4(), srcMAC, reporter)
+		case layers.DNSTypePTR:
+			if strings.Contains(string(rr.Name), "_services._dns-sd._udp") {
+				log.Printf("[*] mDNS service advertised: %s", rr.PTR)
+			}
+		}
+	}
+}
+
+// probeHost performs a TCP SYN scan against the -p port range on a host that
+// was just discovered by ARP, returning the ports that answered SYN+ACK.
+func probeHost(scanner *Interface, mac net.HardwareAddr, ip net.IP) []int {
+	lo, hi, err := parsePortRange(*ports)
+	if err != nil {
+		log.Printf("[!] bad port range %q: %v", *ports, err)
+		return nil
+	}
+
+	handle, err := pcap.OpenLive(scanner.iface.Name, 1024, false, time.Second)
+	if err != nil {
+		log.Printf("[!] SYN scan on %s: %v", ip, err)
+		return nil
+	}
+	defer handle.Close()
+
+	if err := handle.SetBPFFilter(fmt.Sprintf("tcp and src host %s", ip)); err != nil {
+		log.Printf("[!] SYN scan on %s: %v", ip, err)
+		return nil
+	}
+
+	dstMAC := mac
+	if !ip.Mask(scanner.netmask).Equal(scanner.ip.Mask(scanner.netmask)) {
+		if gw, ok := lookupMAC(gatewayIP(scanner)); ok {
+			dstMAC = gw
+		}
+	}
+
+	eth := layers.Ethernet{
+		SrcMAC:       scanner.iface.HardwareAddr,
+		DstMAC:       dstMAC,
+		EthernetType: layers.EthernetTypeIPv4,
+	}
+	ip4 := layers.IPv4{
+		Version:  4,
+		TTL:      64,
+		Protocol: layers.IPProtocolTCP,
+		SrcIP:    scanner.ip,
+		DstIP:    ip,
+	}
+	srcPort := layers.TCPPort(1024 + rand.Intn(64512))
+	tcp := layers.TCP{
+		SrcPort: srcPort,
+		SYN:     true,
+		Window:  14600,
+	}
+	tcp.SetNetworkLayerForChecksum(&ip4)
+
+	buf := gopacket.NewSerializeBuffer()
+	opts := gopacket.SerializeOptions{FixLengths: true, ComputeChecksums: true}
+
+	replies := make(chan *layers.TCP, hi-lo+1)
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		src := gopacket.NewPacketSource(handle, layers.LayerTypeEthernet)
+		for packet := range src.Packets() {
+			if tcpLayer := packet.Layer(layers.LayerTypeTCP); tcpLayer != nil {
+				select {
+				case replies <- tcpLayer.(*layers.TCP):
+				case <-done:
+					return
+				}
+			}
+		}
+	}()
+
+	for port := lo; port <= hi; port++ {
+		tcp.DstPort = layers.TCPPort(port)
+		buf.Clear()
+		if err := gopacket.SerializeLayers(buf, opts, &eth, &ip4, &tcp); err != nil {
+			continue
+		}
+		handle.WritePacketData(buf.Bytes())
+	}
+
+	var open []int
+	deadline := time.After(2 * time.Second)
+collect:
+	for {
+		select {
+		case reply := <-replies:
+			if reply.DstPort != srcPort {
+				continue
+			}
+			if reply.SYN && reply.ACK {
+				open = append(open, int(reply.SrcPort))
+			}
+		case <-deadline:
+			break collect
+		}
+	}
+
+	return open
+}
+
+// parsePortRange parses a "lo-hi" port range flag, e.g. "22-1024".
+func parsePortRange(s string) (lo, hi int, err error) {
+	parts := strings.SplitN(s, "-", 2)
+	if lo, err = strconv.Atoi(parts[0]); err != nil {
+		return 0, 0, err
+	}
+	if len(parts) == 1 {
+		hi = lo
+	} else if hi, err = strconv.Atoi(parts[1]); err != nil {
+		return 0, 0, err
+	}
+
+	if lo < 1 || hi > 65535 || lo > hi {
+		return 0, 0, fmt.Errorf("invalid port range %q", s)
+	}
+
+	return lo, hi, nil
+}
+
+// gatewayIP guesses the default gateway as the first usable host on the subnet.
+func gatewayIP(scanner *Interface) net.IP {
+	bip := binary.BigEndian.Uint32([]byte(scanner.ip.To4()))
+	bmask := binary.BigEndian.Uint32([]byte(scanner.netmask))
+	bnet := (bip & bmask) + 1
+
+	var buf [4]byte
+	binary.BigEndian.PutUint32(buf[:], bnet)
+	return net.IP(buf[:])
+}
+
+// lookupMAC returns a previously seen host's MAC address from the ARP table.
+func lookupMAC(ip net.IP) (net.HardwareAddr, bool) {
+	arpMu.Lock()
+	defer arpMu.Unlock()
+	mac, ok := arpTable[ip.String()]
+	return mac, ok
+}
+
+// getIPAddresses returns all IP addresses on a subnet
+func getIPAddresses(ip *net.IP, mask *net.IPMask) (out []net.IP) {
+	bip := binary.BigEndian.Uint32([]byte(*ip))
+	bmask := binary.BigEndian.Uint32([]byte(*mask))
+	bnet := bip & bmask
+	bbroadcast := bnet | ^bmask
+
+	for bnet++; bnet < bbroadcast; bnet++ {
+		var buf [4]byte
+		binary.BigEndian.PutUint32(buf[:], bnet)
+		out = append(out, net.IP(buf[:]))
+	}
+	return
+}
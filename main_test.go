@@ -0,0 +1,109 @@
+package main
+
+import (
+	"net"
+	"testing"
+
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+	"github.com/jonasbostoen/go-fingerprint/report"
+)
+
+// TestMDNSDecode builds a synthetic UDP/5353 mDNS answer packet the way a
+// captured one would look, decodes it the way passiveScan does, and feeds
+// it through handleMDNS. It exists to catch the port 5353 -> DNS layer
+// registration regressing, since gopacket only maps UDP/53 to DNS by
+// default and handleMDNS would silently never run without it.
+func TestMDNSDecode(t *testing.T) {
+	eth := layers.Ethernet{
+		SrcMAC:       net.HardwareAddr{0x02, 0x00, 0x00, 0x00, 0x00, 0x01},
+		DstMAC:       net.HardwareAddr{0x01, 0x00, 0x5e, 0x00, 0x00, 0xfb},
+		EthernetType: layers.EthernetTypeIPv4,
+	}
+	ip4 := layers.IPv4{
+		Version:  4,
+		TTL:      255,
+		Protocol: layers.IPProtocolUDP,
+		SrcIP:    net.IPv4(192, 168, 1, 42),
+		DstIP:    net.IPv4(224, 0, 0, 251),
+	}
+	udp := layers.UDP{
+		SrcPort: 5353,
+		DstPort: 5353,
+	}
+	udp.SetNetworkLayerForChecksum(&ip4)
+	dns := layers.DNS{
+		QR: true,
+		Answers: []layers.DNSResourceRecord{
+			{
+				Name:  []byte("pi.local"),
+				Type:  layers.DNSTypeA,
+				Class: layers.DNSClassIN,
+				IP:    net.IPv4(192, 168, 1, 42),
+			},
+		},
+	}
+
+	buf := gopacket.NewSerializeBuffer()
+	opts := gopacket.SerializeOptions{FixLengths: true, ComputeChecksums: true}
+	if err := gopacket.SerializeLayers(buf, opts, &eth, &ip4, &udp, &dns); err != nil {
+		t.Fatalf("serialize: %v", err)
+	}
+
+	packet := gopacket.NewPacket(buf.Bytes(), layers.LayerTypeEthernet, gopacket.Default)
+	dnsLayer := packet.Layer(layers.LayerTypeDNS)
+	if dnsLayer == nil {
+		t.Fatal("expected a UDP/5353 packet to decode to a DNS layer, got nil (is port 5353 registered?)")
+	}
+
+	scanner := &Interface{iface: &net.Interface{}}
+	handleMDNS(scanner, packet, dnsLayer.(*layers.DNS), discardReporter{})
+
+	if got := hostnames["192.168.1.42"]; got != "pi.local" {
+		t.Fatalf("hostnames[192.168.1.42] = %q, want %q", got, "pi.local")
+	}
+}
+
+// TestParsePortRange covers the valid "-p" forms plus the inverted and
+// out-of-range inputs that used to slip past validation and panic the
+// channel allocation in probeHost (make(chan T, hi-lo+1) with hi < lo).
+func TestParsePortRange(t *testing.T) {
+	cases := []struct {
+		in      string
+		lo, hi  int
+		wantErr bool
+	}{
+		{in: "80", lo: 80, hi: 80},
+		{in: "22-1024", lo: 22, hi: 1024},
+		{in: "1-65535", lo: 1, hi: 65535},
+		{in: "1024-22", wantErr: true},
+		{in: "0-80", wantErr: true},
+		{in: "80-65536", wantErr: true},
+		{in: "abc", wantErr: true},
+		{in: "80-abc", wantErr: true},
+	}
+
+	for _, c := range cases {
+		lo, hi, err := parsePortRange(c.in)
+		if c.wantErr {
+			if err == nil {
+				t.Errorf("parsePortRange(%q) = %d, %d, nil; want error", c.in, lo, hi)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parsePortRange(%q) unexpected error: %v", c.in, err)
+			continue
+		}
+		if lo != c.lo || hi != c.hi {
+			t.Errorf("parsePortRange(%q) = %d, %d; want %d, %d", c.in, lo, hi, c.lo, c.hi)
+		}
+	}
+}
+
+// discardReporter is a no-op report.Reporter for tests.
+type discardReporter struct{}
+
+func (discardReporter) ReportHost(report.Host) error                   { return nil }
+func (discardReporter) WritePacket([]byte, gopacket.CaptureInfo) error { return nil }
+func (discardReporter) Close() error                                  { return nil }
@@ -0,0 +1,81 @@
+// Package ouidb resolves an Ethernet MAC address to the hardware vendor
+// that registered its OUI with the IEEE, using a vendor database embedded
+// into the binary at build time.
+package ouidb
+
+import (
+	"bufio"
+	"bytes"
+	_ "embed"
+	"encoding/hex"
+	"net"
+	"sort"
+	"strings"
+)
+
+//go:embed mac-fab.txt
+var ouiData []byte
+
+// finePrefix is an IEEE MA-M (28-bit) or MA-S (36-bit) assignment, which
+// shares its /24 MA-L OUI with other vendors and is disambiguated by a
+// longer hex prefix than the base map affords.
+type finePrefix struct {
+	prefix string // uppercase hex, no separators: 7 digits (28-bit) or 9 digits (36-bit)
+	vendor string
+}
+
+var (
+	index24 = make(map[[3]byte]string)
+	fine    []finePrefix
+)
+
+func init() {
+	scanner := bufio.NewScanner(bytes.NewReader(ouiData))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+
+		prefix := strings.ToUpper(fields[0])
+		name := strings.Join(fields[1:], " ")
+
+		switch len(prefix) {
+		case 6:
+			raw, err := hex.DecodeString(prefix)
+			if err != nil {
+				continue
+			}
+			var key [3]byte
+			copy(key[:], raw)
+			index24[key] = name
+		case 7, 9:
+			fine = append(fine, finePrefix{prefix: prefix, vendor: name})
+		}
+	}
+
+	// Longest prefix first so Lookup tries MA-S before MA-M.
+	sort.Slice(fine, func(i, j int) bool {
+		return len(fine[i].prefix) > len(fine[j].prefix)
+	})
+}
+
+// Lookup returns the vendor name for mac's OUI, preferring a finer-grained
+// MA-M/MA-S assignment over the base MA-L /24 entry when both match, and
+// falling back to the /24 entry when no finer match exists.
+func Lookup(mac net.HardwareAddr) string {
+	if len(mac) < 3 {
+		return ""
+	}
+
+	hexMAC := strings.ToUpper(hex.EncodeToString(mac))
+	for _, f := range fine {
+		if strings.HasPrefix(hexMAC, f.prefix) {
+			return f.vendor
+		}
+	}
+
+	var key [3]byte
+	copy(key[:], mac[:3])
+	return index24[key]
+}
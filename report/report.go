@@ -0,0 +1,198 @@
+// Package report defines pluggable output formats for discovered hosts.
+// Reporters stream results as a scan runs rather than buffering until it
+// ends, so long multi-interface runs stay useful if interrupted.
+package report
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+	"github.com/google/gopacket/pcapgo"
+)
+
+// Host is a single discovered host, as passed to a Reporter.
+type Host struct {
+	Timestamp time.Time `json:"timestamp"`
+	Interface string    `json:"interface"`
+	IPv4      string    `json:"ipv4"`
+	MAC       string    `json:"mac"`
+	Vendor    string    `json:"vendor,omitempty"`
+	Hostname  string    `json:"hostname,omitempty"`
+	Ports     []int     `json:"ports,omitempty"`
+}
+
+// Reporter streams discovered hosts and, for the pcap reporter, the raw
+// ARP (and, in -passive mode, mDNS) packets that found them.
+type Reporter interface {
+	ReportHost(h Host) error
+	WritePacket(data []byte, ci gopacket.CaptureInfo) error
+	Close() error
+}
+
+// New returns the Reporter for the given -o mode ("", "table", "json",
+// "csv" or "pcap"). path names the destination file for json/csv (empty
+// means stdout) and the pcap; it is otherwise ignored.
+func New(mode, path string) (Reporter, error) {
+	switch mode {
+	case "", "table":
+		return &tableReporter{}, nil
+	case "json":
+		w, err := output(path)
+		if err != nil {
+			return nil, err
+		}
+		return &jsonReporter{w: w, enc: json.NewEncoder(w)}, nil
+	case "csv":
+		w, err := output(path)
+		if err != nil {
+			return nil, err
+		}
+		return &csvReporter{w: w, cw: csv.NewWriter(w)}, nil
+	case "pcap":
+		if path == "" {
+			path = "scan.pcap"
+		}
+		f, err := os.Create(path)
+		if err != nil {
+			return nil, err
+		}
+		w := pcapgo.NewWriter(f)
+		if err := w.WriteFileHeader(65536, layers.LinkTypeEthernet); err != nil {
+			f.Close()
+			return nil, err
+		}
+		return &pcapReporter{f: f, w: w}, nil
+	default:
+		return nil, fmt.Errorf("unknown output mode %q", mode)
+	}
+}
+
+func output(path string) (io.Writer, error) {
+	if path == "" {
+		return os.Stdout, nil
+	}
+	return os.Create(path)
+}
+
+func closeIfFile(w io.Writer) error {
+	if w == os.Stdout {
+		return nil
+	}
+	if c, ok := w.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// tableReporter prints the tool's original plain-text table, with an added
+// Open Ports column.
+type tableReporter struct {
+	header sync.Once
+	mu     sync.Mutex
+}
+
+func (t *tableReporter) ReportHost(h Host) error {
+	t.header.Do(func() {
+		fmt.Printf("%-20s %-20s %-30s %s\n", "IPv4", "MAC", "Hardware", "Open Ports")
+		fmt.Println("===================================================================")
+	})
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	fmt.Printf("%-20v %-20v %-30s %v\n", h.IPv4, h.MAC, h.Vendor, h.Ports)
+	return nil
+}
+
+func (t *tableReporter) WritePacket(data []byte, ci gopacket.CaptureInfo) error { return nil }
+
+func (t *tableReporter) Close() error { return nil }
+
+// jsonReporter emits one JSON object per host, newline-delimited.
+type jsonReporter struct {
+	w   io.Writer
+	enc *json.Encoder
+	mu  sync.Mutex
+}
+
+func (j *jsonReporter) ReportHost(h Host) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.enc.Encode(h)
+}
+
+func (j *jsonReporter) WritePacket(data []byte, ci gopacket.CaptureInfo) error { return nil }
+
+func (j *jsonReporter) Close() error { return closeIfFile(j.w) }
+
+// csvReporter emits one CSV row per host, with a header on the first write.
+type csvReporter struct {
+	w      io.Writer
+	cw     *csv.Writer
+	header sync.Once
+	mu     sync.Mutex
+}
+
+var csvHeader = []string{"timestamp", "interface", "ipv4", "mac", "vendor", "hostname", "ports"}
+
+func (c *csvReporter) ReportHost(h Host) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var headerErr error
+	c.header.Do(func() { headerErr = c.cw.Write(csvHeader) })
+	if headerErr != nil {
+		return headerErr
+	}
+
+	ports := make([]string, len(h.Ports))
+	for i, p := range h.Ports {
+		ports[i] = strconv.Itoa(p)
+	}
+
+	if err := c.cw.Write([]string{
+		h.Timestamp.Format(time.RFC3339),
+		h.Interface,
+		h.IPv4,
+		h.MAC,
+		h.Vendor,
+		h.Hostname,
+		strings.Join(ports, ";"),
+	}); err != nil {
+		return err
+	}
+	c.cw.Flush()
+	return c.cw.Error()
+}
+
+func (c *csvReporter) WritePacket(data []byte, ci gopacket.CaptureInfo) error { return nil }
+
+func (c *csvReporter) Close() error { return closeIfFile(c.w) }
+
+// pcapReporter writes every packet passed to WritePacket to a .pcap file so
+// a run is reproducible and inspectable in Wireshark: the sent ARP requests
+// and received ARP replies of an active scan, or all the ARP and mDNS
+// traffic sniffed in -passive mode.
+type pcapReporter struct {
+	f  *os.File
+	w  *pcapgo.Writer
+	mu sync.Mutex
+}
+
+func (p *pcapReporter) ReportHost(h Host) error { return nil }
+
+func (p *pcapReporter) WritePacket(data []byte, ci gopacket.CaptureInfo) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.w.WritePacket(ci, data)
+}
+
+func (p *pcapReporter) Close() error { return p.f.Close() }
@@ -0,0 +1,65 @@
+package report
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+// TestReportHostConcurrent hammers ReportHost from many goroutines at once
+// for every reporter that writes shared state, so a `go test -race` run
+// catches a missing mutex the way tableReporter's was: it looked fine in
+// single-host testing because the data race only shows up under concurrent
+// examineMAC goroutines.
+func TestReportHostConcurrent(t *testing.T) {
+	dir := t.TempDir()
+
+	reporters := map[string]func() Reporter{
+		"table": func() Reporter { return &tableReporter{} },
+		"json": func() Reporter {
+			r, err := New("json", filepath.Join(dir, "out.json"))
+			if err != nil {
+				t.Fatalf("New(json): %v", err)
+			}
+			return r
+		},
+		"csv": func() Reporter {
+			r, err := New("csv", filepath.Join(dir, "out.csv"))
+			if err != nil {
+				t.Fatalf("New(csv): %v", err)
+			}
+			return r
+		},
+	}
+
+	for name, newReporter := range reporters {
+		name, newReporter := name, newReporter
+		t.Run(name, func(t *testing.T) {
+			if name == "table" {
+				// tableReporter writes straight to stdout; keep the test
+				// output clean while still exercising the real code path.
+				old := os.Stdout
+				_, w, err := os.Pipe()
+				if err != nil {
+					t.Fatalf("os.Pipe: %v", err)
+				}
+				os.Stdout = w
+				defer func() { os.Stdout = old; w.Close() }()
+			}
+
+			r := newReporter()
+			defer r.Close()
+
+			var wg sync.WaitGroup
+			for i := 0; i < 50; i++ {
+				wg.Add(1)
+				go func(i int) {
+					defer wg.Done()
+					r.ReportHost(Host{IPv4: "10.0.0.1", MAC: "aa:bb:cc:dd:ee:ff"})
+				}(i)
+			}
+			wg.Wait()
+		})
+	}
+}